@@ -0,0 +1,154 @@
+package signalr
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/rotisserie/eris"
+)
+
+// Backplane lets a group of Server instances behind a load balancer share invocation traffic,
+// so Clients.All(), Clients.Group(...) and Clients.Client(id) reach connections attached to any
+// node, not just the one that received the originating call.
+//
+// Publish must only be called for a broadcast send (Clients.All/Group/Client); ordinary unary
+// RPC replies and StreamItem pushes to the calling client are local to the connection that
+// issued them and must never reach the backplane.
+type Backplane interface {
+	// Publish fans msg out to every handler subscribed to the backplane, the publishing node's
+	// own included; subscribers tell their own messages apart by OriginID. msg is one of
+	// invocationMessage, streamItemMessage or completionMessage. groupOrConnID is the group
+	// name or connection ID the hub addressed (Clients.Group("g") or Clients.Client("id")), or
+	// "" for Clients.All().
+	Publish(groupOrConnID string, msg interface{}) error
+	// Subscribe registers handler to be called for every backplaneMessage published to the
+	// backplane, including the ones this node published itself. WithBackplane calls it once per
+	// Server, during Server construction, and drops that Server's own messages by OriginID.
+	Subscribe(handler func(msg backplaneMessage)) error
+}
+
+// backplaneMessage is what a Backplane delivers to peer nodes: the original frame plus enough
+// addressing information for each node to decide which of its local connections, if any,
+// should receive it.
+type backplaneMessage struct {
+	// OriginID identifies the node that published this message, so a node can ignore messages
+	// it published itself: it already delivered them to its own local connections directly,
+	// and redelivering them via the backplane round-trip would duplicate them.
+	OriginID string `json:"originId"`
+	// GroupOrConnID mirrors the groupOrConnID a node passed to Publish: a group name, a
+	// connection ID, or "" for Clients.All().
+	GroupOrConnID string `json:"groupOrConnId"`
+	// MessageType discriminates Message's concrete type, since Backplane implementations that
+	// serialize messages (e.g. redisBackplane) can't otherwise recover it from an interface{}.
+	MessageType string `json:"messageType"`
+	// Message is the invocationMessage, streamItemMessage or completionMessage frame to
+	// re-dispatch to local connections that match GroupOrConnID.
+	Message interface{} `json:"message"`
+}
+
+const (
+	backplaneMessageTypeInvocation = "invocation"
+	backplaneMessageTypeStreamItem = "streamItem"
+	backplaneMessageTypeCompletion = "completion"
+)
+
+// generateBackplaneNodeID returns a fresh random id suitable for tagging a single node's
+// outgoing backplane traffic. It must be called once per node, not once per process: a Backplane
+// instance (e.g. one NewInMemoryBackplane) is routinely shared by several Servers in the same
+// process to simulate several nodes in tests, so a process-wide id would make every Server
+// appear to be the same node, and dispatchBackplaneMessage would discard every message
+// regardless of which Server actually published it. WithBackplane calls this once per Server.
+func generateBackplaneNodeID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand failing is effectively unrecoverable; fall back to a constant that at
+		// least keeps every message from this call self-identified the same (if wrong) way,
+		// rather than panicking.
+		return "unknown-node"
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// newBackplaneMessage stamps msg with originID and its concrete-type tag, ready for a Backplane
+// implementation to publish.
+func newBackplaneMessage(originID string, groupOrConnID string, msg interface{}) (backplaneMessage, error) {
+	messageType, err := backplaneMessageTypeOf(msg)
+	if err != nil {
+		return backplaneMessage{}, err
+	}
+	return backplaneMessage{
+		OriginID:      originID,
+		GroupOrConnID: groupOrConnID,
+		MessageType:   messageType,
+		Message:       msg,
+	}, nil
+}
+
+func backplaneMessageTypeOf(msg interface{}) (string, error) {
+	switch msg.(type) {
+	case invocationMessage:
+		return backplaneMessageTypeInvocation, nil
+	case streamItemMessage:
+		return backplaneMessageTypeStreamItem, nil
+	case completionMessage:
+		return backplaneMessageTypeCompletion, nil
+	default:
+		return "", eris.Errorf("backplane: cannot publish %T", msg)
+	}
+}
+
+// backplaneOriginPublisher is implemented by every Backplane this package ships
+// (inMemoryBackplane, redisBackplane) so originTaggingBackplane can publish a message under an
+// explicit origin id, rather than whatever default the underlying Backplane would otherwise
+// stamp it with.
+type backplaneOriginPublisher interface {
+	publishAs(originID string, groupOrConnID string, msg interface{}) error
+}
+
+// originTaggingBackplane scopes a shared Backplane's outgoing messages to one Server. Several
+// Servers in the same process commonly share one underlying Backplane instance (e.g. one
+// NewInMemoryBackplane, to simulate several nodes in a test without a real broker); wrapping
+// each Server's use of it in one of these, with its own nodeID, keeps their messages distinct
+// instead of collapsing onto one id that every Server, or the whole process, would otherwise
+// share.
+type originTaggingBackplane struct {
+	Backplane
+	nodeID string
+}
+
+func (o *originTaggingBackplane) Publish(groupOrConnID string, msg interface{}) error {
+	origin, ok := o.Backplane.(backplaneOriginPublisher)
+	if !ok {
+		return eris.Errorf("backplane: %T does not support per-node origin tagging", o.Backplane)
+	}
+	return origin.publishAs(o.nodeID, groupOrConnID, msg)
+}
+
+// WithBackplane wires b into Server so hub invocations fan out across every node sharing it.
+// Every Server that calls WithBackplane, even against the same b, gets its own node id, so
+// dispatchBackplaneMessage can always tell this Server's own messages apart from a peer node's
+// (see originTaggingBackplane).
+func WithBackplane(b Backplane) ServerOption {
+	return func(server *Server) error {
+		nodeID := generateBackplaneNodeID()
+		server.backplane = &originTaggingBackplane{Backplane: b, nodeID: nodeID}
+		return b.Subscribe(func(msg backplaneMessage) {
+			if msg.OriginID == nodeID {
+				return
+			}
+			server.dispatchBackplaneMessage(msg)
+		})
+	}
+}
+
+// dispatchBackplaneMessage re-delivers a frame published by a peer node to this node's local
+// connections: every connection if msg.GroupOrConnID is "", the members of that group, or the
+// single connection with that ID. WithBackplane's Subscribe handler already filters out this
+// Server's own messages before they ever reach here.
+func (s *Server) dispatchBackplaneMessage(msg backplaneMessage) {
+	for _, conn := range s.localConnectionsFor(msg.GroupOrConnID) {
+		if err := conn.dispatch(msg.Message); err != nil {
+			_ = s.info.Log(evt, msgSend, "message", "backplane redispatch failed", "error", err)
+		}
+	}
+}
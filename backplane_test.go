@@ -0,0 +1,109 @@
+package signalr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeBackplaneMessagePreservesConcreteType(t *testing.T) {
+	original := invocationMessage{
+		Type:         1,
+		InvocationID: "1",
+		Target:       "broadcast",
+		Arguments:    []interface{}{"hello"},
+	}
+	envelope, err := newBackplaneMessage("node-a", "", original)
+	if err != nil {
+		t.Fatalf("newBackplaneMessage failed: %v", err)
+	}
+	wire, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	decoded, err := decodeBackplaneMessage(wire)
+	if err != nil {
+		t.Fatalf("decodeBackplaneMessage failed: %v", err)
+	}
+	message, ok := decoded.Message.(invocationMessage)
+	if !ok {
+		t.Fatalf("expected invocationMessage, got %T", decoded.Message)
+	}
+	if message.Target != "broadcast" {
+		t.Fatalf("expected Target %q, got %q", "broadcast", message.Target)
+	}
+	if decoded.OriginID != "node-a" {
+		t.Fatalf("expected OriginID %q, got %q", "node-a", decoded.OriginID)
+	}
+}
+
+func TestInMemoryBackplaneStampsOrigin(t *testing.T) {
+	b := NewInMemoryBackplane()
+	nodeID := b.(*inMemoryBackplane).nodeID
+	received := make(chan backplaneMessage, 1)
+	if err := b.Subscribe(func(msg backplaneMessage) { received <- msg }); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if err := b.Publish("group1", invocationMessage{Type: 1, Target: "m"}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	msg := <-received
+	if msg.OriginID != nodeID {
+		t.Fatalf("expected published message to carry this instance's origin ID")
+	}
+	if msg.GroupOrConnID != "group1" {
+		t.Fatalf("expected GroupOrConnID %q, got %q", "group1", msg.GroupOrConnID)
+	}
+}
+
+// TestOriginTaggingBackplaneScopesIDPerServer simulates WithBackplane's exact setup for two
+// Servers sharing one Backplane instance -- NewInMemoryBackplane's documented pattern for
+// exercising multi-node fan-out in tests -- and confirms each gets its own origin id, that node
+// B receives node A's broadcast, and that node A does not receive it back. This is the scenario
+// a process-wide origin id
+// broke: every message looked self-published to every node sharing the instance.
+func TestOriginTaggingBackplaneScopesIDPerServer(t *testing.T) {
+	shared := NewInMemoryBackplane()
+
+	nodeA := &originTaggingBackplane{Backplane: shared, nodeID: generateBackplaneNodeID()}
+	nodeB := &originTaggingBackplane{Backplane: shared, nodeID: generateBackplaneNodeID()}
+	if nodeA.nodeID == nodeB.nodeID {
+		t.Fatalf("expected two WithBackplane calls to generate distinct node ids")
+	}
+
+	receivedByA := make(chan backplaneMessage, 1)
+	receivedByB := make(chan backplaneMessage, 1)
+	if err := shared.Subscribe(func(msg backplaneMessage) {
+		if msg.OriginID != nodeA.nodeID {
+			receivedByA <- msg
+		}
+	}); err != nil {
+		t.Fatalf("Subscribe for node A failed: %v", err)
+	}
+	if err := shared.Subscribe(func(msg backplaneMessage) {
+		if msg.OriginID != nodeB.nodeID {
+			receivedByB <- msg
+		}
+	}); err != nil {
+		t.Fatalf("Subscribe for node B failed: %v", err)
+	}
+
+	if err := nodeA.Publish("", invocationMessage{Type: 1, Target: "fromA"}); err != nil {
+		t.Fatalf("Publish from node A failed: %v", err)
+	}
+
+	select {
+	case msg := <-receivedByB:
+		if invoc, ok := msg.Message.(invocationMessage); !ok || invoc.Target != "fromA" {
+			t.Fatalf("expected node B to receive node A's message, got %+v", msg)
+		}
+	default:
+		t.Fatalf("expected node B to receive node A's broadcast")
+	}
+
+	select {
+	case msg := <-receivedByA:
+		t.Fatalf("expected node A not to receive its own broadcast back, got %+v", msg)
+	default:
+	}
+}
@@ -0,0 +1,612 @@
+package signalr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"golang.org/x/net/websocket"
+)
+
+// AccessTokenProvider supplies a bearer token Client attaches to the negotiate request and to
+// every transport connection, so a Client can talk to hubs guarded by JWT auth (e.g. an ASP.NET
+// Core hub behind [Authorize]).
+type AccessTokenProvider func() (string, error)
+
+// ClientOption configures a Client. Pass options to Dial.
+type ClientOption func(*Client) error
+
+// WithAccessTokenProvider attaches an Authorization: Bearer header, refreshed via provider, to
+// the negotiate request and to every (re)connect attempt.
+func WithAccessTokenProvider(provider AccessTokenProvider) ClientOption {
+	return func(c *Client) error {
+		c.tokenProvider = provider
+		return nil
+	}
+}
+
+// WithClientHubProtocol selects the HubProtocol the handshake frame asks the server to use.
+// The default is the built-in "json" protocol.
+func WithClientHubProtocol(protocol HubProtocol) ClientOption {
+	return func(c *Client) error {
+		c.protocol = protocol
+		return nil
+	}
+}
+
+// WithAutoReconnect enables automatic reconnect with exponential backoff (capped at max,
+// starting at initial) whenever the connection is lost for a reason other than Client.Close.
+func WithAutoReconnect(initial time.Duration, max time.Duration) ClientOption {
+	return func(c *Client) error {
+		c.reconnect = true
+		c.reconnectMin = initial
+		c.reconnectMax = max
+		return nil
+	}
+}
+
+// Client is a Go SignalR client: it dials a remote hub and exposes the same
+// invoke/send/stream/on-handler shape Server's HubContext gives a hub author on the server side.
+type Client struct {
+	mx            sync.RWMutex
+	baseURL       string
+	httpClient    *http.Client
+	tokenProvider AccessTokenProvider
+	protocol      HubProtocol
+
+	reconnect    bool
+	reconnectMin time.Duration
+	reconnectMax time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	conn     hubConnection
+	nextID   uint64
+	pending  map[string]chan invocationResult
+	streams  map[string]*clientStream
+	handlers map[string]reflect.Value
+}
+
+type invocationResult struct {
+	value interface{}
+	err   error
+}
+
+// clientStream guards the channel backing a single Stream() call. A StreamItem push (from
+// dispatch, on the receiveLoop goroutine) and the stream's closure (on Completion, on the
+// caller's ctx being canceled, or on the connection failing) can happen concurrently; without
+// coordination a push racing a close can end up sending on an already-closed channel and panic
+// the whole process. close cancels ctx first, so a push blocked in its select unblocks right
+// away, then takes mu to close the channel exactly once — by the time it does, no push is still
+// attempting a send.
+type clientStream struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	ch     chan interface{}
+	mu     sync.Mutex
+	closed bool
+}
+
+func newClientStream() *clientStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &clientStream{ctx: ctx, cancel: cancel, ch: make(chan interface{})}
+}
+
+func (s *clientStream) push(item interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- item:
+	case <-s.ctx.Done():
+	}
+}
+
+func (s *clientStream) close() {
+	s.cancel()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.ch)
+}
+
+// Dial performs the SignalR negotiate handshake against {url}/negotiate, picks a transport
+// (WebSockets first, then LongPolling), performs the SignalR handshake frame and returns a
+// connected Client. ServerSentEvents is recognized but never connects: dialSSE always returns
+// an error, so a server offering it is reached over another transport it offers, or not at all.
+func Dial(ctx context.Context, rawURL string, options ...ClientOption) (*Client, error) {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(rawURL, "/"),
+		httpClient: http.DefaultClient,
+		protocol:   &jsonHubProtocol{},
+		pending:    make(map[string]chan invocationResult),
+		streams:    make(map[string]*clientStream),
+		handlers:   make(map[string]reflect.Value),
+	}
+	for _, option := range options {
+		if err := option(c); err != nil {
+			return nil, err
+		}
+	}
+	c.ctx, c.cancel = context.WithCancel(ctx)
+
+	if err := c.connect(); err != nil {
+		c.cancel()
+		return nil, err
+	}
+
+	go c.receiveLoop()
+	if c.reconnect {
+		go c.watchConnection()
+	}
+	return c, nil
+}
+
+func (c *Client) connect() error {
+	negotiated, err := c.negotiate()
+	if err != nil {
+		return eris.Wrap(err, "signalr: negotiate failed")
+	}
+	connection, err := c.dialTransport(negotiated)
+	if err != nil {
+		return eris.Wrap(err, "signalr: transport dial failed")
+	}
+	if err := c.handshake(connection); err != nil {
+		return eris.Wrap(err, "signalr: handshake failed")
+	}
+	conn := newHubConnection(connection, c.protocol, 1<<20, &noopLogger{})
+	conn.Start(defaultKeepAliveInterval, defaultClientTimeoutInterval)
+	c.mx.Lock()
+	c.conn = conn
+	c.mx.Unlock()
+	return nil
+}
+
+func (c *Client) negotiate() (negotiateResponse, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, c.baseURL+"/negotiate", nil)
+	if err != nil {
+		return negotiateResponse{}, err
+	}
+	if err := c.authorize(req); err != nil {
+		return negotiateResponse{}, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return negotiateResponse{}, err
+	}
+	defer resp.Body.Close()
+	var negotiated negotiateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&negotiated); err != nil {
+		return negotiateResponse{}, err
+	}
+	return negotiated, nil
+}
+
+func (c *Client) authorize(req *http.Request) error {
+	if c.tokenProvider == nil {
+		return nil
+	}
+	token, err := c.tokenProvider()
+	if err != nil {
+		return eris.Wrap(err, "signalr: access token provider failed")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// dialTransport tries each transport the server negotiated, in this client's order of
+// preference (WebSockets, ServerSentEvents, LongPolling) whatever order the server listed them
+// in, and returns the first one that dials successfully. A transport this client doesn't
+// recognize is skipped; one it recognizes but fails to dial (e.g. ServerSentEvents, which
+// dialSSE always fails) falls through to the next rather than failing the whole connect, so a
+// server configured for SSE+LongPolling only still lets the client connect via LongPolling.
+func (c *Client) dialTransport(negotiated negotiateResponse) (Connection, error) {
+	offered := make(map[string]bool, len(negotiated.AvailableTransports))
+	for _, available := range negotiated.AvailableTransports {
+		offered[available.Transport] = true
+	}
+	var lastErr error
+	for _, name := range []string{"WebSockets", "ServerSentEvents", "LongPolling"} {
+		if !offered[name] {
+			continue
+		}
+		switch name {
+		case "WebSockets":
+			conn, err := c.dialWebSocket(negotiated.ConnectionID)
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		case "ServerSentEvents":
+			conn, err := c.dialSSE(negotiated.ConnectionID)
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		case "LongPolling":
+			return c.dialLongPolling(negotiated.ConnectionID), nil
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, eris.New("signalr: server offered no transport this client supports")
+}
+
+func (c *Client) dialWebSocket(connectionID string) (Connection, error) {
+	wsURL := strings.Replace(c.baseURL, "http", "ws", 1) + "?id=" + url.QueryEscape(connectionID)
+	config, err := websocket.NewConfig(wsURL, c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if c.tokenProvider != nil {
+		token, err := c.tokenProvider()
+		if err != nil {
+			return nil, eris.Wrap(err, "signalr: access token provider failed")
+		}
+		config.Header.Set("Authorization", "Bearer "+token)
+	}
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &webSocketConnection{ws, nil, connectionID}, nil
+}
+
+func (c *Client) dialSSE(connectionID string) (Connection, error) {
+	return nil, eris.New("signalr: ServerSentEvents client transport not yet implemented")
+}
+
+func (c *Client) dialLongPolling(connectionID string) Connection {
+	return newClientLongPollingConnection(c, connectionID)
+}
+
+// clientLongPollingConnection implements Connection for the LongPolling transport by issuing a
+// blocking GET against {baseURL}?id=... for every frame the server has to deliver, and POSTing
+// every outbound frame to that same URL, mirroring the wire contract longPollingTransport's
+// handlePoll/handleSend expect on the server side. Unlike longPollingConnection (the server-side
+// adapter, which only talks to channels an HTTP handler feeds), this is the HTTP driver itself.
+type clientLongPollingConnection struct {
+	client       *Client
+	connectionID string
+	pollURL      string
+	pending      []byte
+}
+
+func newClientLongPollingConnection(client *Client, connectionID string) *clientLongPollingConnection {
+	return &clientLongPollingConnection{
+		client:       client,
+		connectionID: connectionID,
+		pollURL:      client.baseURL + "?id=" + url.QueryEscape(connectionID),
+	}
+}
+
+// Read blocks on a GET to pollURL until the server has a frame to deliver, carrying over
+// whatever doesn't fit in p the same way sseConnection/longPollingConnection do.
+func (c *clientLongPollingConnection) Read(p []byte) (int, error) {
+	if len(c.pending) == 0 {
+		frame, err := c.poll()
+		if err != nil {
+			return 0, err
+		}
+		c.pending = frame
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *clientLongPollingConnection) poll() ([]byte, error) {
+	req, err := http.NewRequestWithContext(c.client.ctx, http.MethodGet, c.pollURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.client.authorize(req); err != nil {
+		return nil, err
+	}
+	resp, err := c.client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusGone {
+		return nil, eris.New("clientLongPollingConnection: connection closed by server")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, eris.Errorf("clientLongPollingConnection: unexpected poll response status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (c *clientLongPollingConnection) Write(p []byte) (int, error) {
+	req, err := http.NewRequestWithContext(c.client.ctx, http.MethodPost, c.pollURL, bytes.NewReader(p))
+	if err != nil {
+		return 0, err
+	}
+	if err := c.client.authorize(req); err != nil {
+		return 0, err
+	}
+	resp, err := c.client.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, eris.Errorf("clientLongPollingConnection: unexpected send response status %d", resp.StatusCode)
+	}
+	return len(p), nil
+}
+
+func (c *clientLongPollingConnection) ConnectionID() string { return c.connectionID }
+
+func (c *clientLongPollingConnection) Context() context.Context { return c.client.ctx }
+
+// handshake sends the SignalR handshake frame ({"protocol":"...","version":1}\x1e) and waits
+// for the server's empty handshake response before any invocation traffic is exchanged.
+func (c *Client) handshake(connection Connection) error {
+	request := handshakeRequest{Protocol: c.protocol.Name(), Version: 1}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	if _, err := connection.Write(append(body, 0x1e)); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	data := make([]byte, 4096)
+	for {
+		n, err := connection.Read(data)
+		if err != nil {
+			return err
+		}
+		buf.Write(data[:n])
+		if idx := bytes.IndexByte(buf.Bytes(), 0x1e); idx >= 0 {
+			return nil
+		}
+	}
+}
+
+func (c *Client) receiveLoop() {
+	for {
+		conn := c.currentConnection()
+		if conn == nil {
+			return
+		}
+		message, err := conn.Receive()
+		if err != nil {
+			if c.reconnect {
+				return // watchConnection will redial
+			}
+			c.failPending(err)
+			return
+		}
+		c.dispatch(message)
+	}
+}
+
+func (c *Client) currentConnection() hubConnection {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.conn
+}
+
+func (c *Client) dispatch(message interface{}) {
+	switch msg := message.(type) {
+	case completionMessage:
+		c.mx.Lock()
+		ch, ok := c.pending[msg.InvocationID]
+		delete(c.pending, msg.InvocationID)
+		stream, streamOk := c.streams[msg.InvocationID]
+		delete(c.streams, msg.InvocationID)
+		c.mx.Unlock()
+		if ok {
+			var err error
+			if msg.Error != "" {
+				err = eris.New(msg.Error)
+			}
+			ch <- invocationResult{value: msg.Result, err: err}
+			close(ch)
+		}
+		if streamOk {
+			// The server completed a Stream invocation on its own (it drained normally);
+			// close the channel Stream returned so its reader's range/receive loop ends.
+			stream.close()
+		}
+	case streamItemMessage:
+		c.mx.RLock()
+		stream, ok := c.streams[msg.InvocationID]
+		c.mx.RUnlock()
+		if ok {
+			stream.push(msg.Item)
+		}
+	case invocationMessage:
+		c.mx.RLock()
+		handler, ok := c.handlers[msg.Target]
+		c.mx.RUnlock()
+		if ok {
+			invokeHandler(handler, msg.Arguments)
+		}
+	}
+}
+
+func (c *Client) failPending(err error) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	for id, ch := range c.pending {
+		ch <- invocationResult{err: err}
+		close(ch)
+		delete(c.pending, id)
+	}
+	for id, stream := range c.streams {
+		stream.close()
+		delete(c.streams, id)
+	}
+}
+
+// invokeHandler calls handler, a func registered via On, with SignalR arguments decoded into
+// its declared parameter types via reflection.
+func invokeHandler(handler reflect.Value, arguments []interface{}) {
+	handlerType := handler.Type()
+	in := make([]reflect.Value, handlerType.NumIn())
+	for i := range in {
+		paramType := handlerType.In(i)
+		arg := reflect.New(paramType)
+		if i < len(arguments) {
+			if raw, err := json.Marshal(arguments[i]); err == nil {
+				_ = json.Unmarshal(raw, arg.Interface())
+			}
+		}
+		in[i] = arg.Elem()
+	}
+	handler.Call(in)
+}
+
+// Invoke calls method on the remote hub and blocks for its Completion.
+func (c *Client) Invoke(ctx context.Context, method string, args ...interface{}) (interface{}, error) {
+	conn := c.currentConnection()
+	if conn == nil {
+		return nil, eris.New("signalr: not connected")
+	}
+	id := c.newInvocationID()
+	ch := make(chan invocationResult, 1)
+	c.mx.Lock()
+	c.pending[id] = ch
+	c.mx.Unlock()
+	if err := conn.SendInvocation(id, method, args); err != nil {
+		return nil, err
+	}
+	select {
+	case result := <-ch:
+		return result.value, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Send calls method on the remote hub without waiting for a Completion (fire-and-forget).
+func (c *Client) Send(method string, args ...interface{}) error {
+	conn := c.currentConnection()
+	if conn == nil {
+		return eris.New("signalr: not connected")
+	}
+	return conn.SendInvocation(c.newInvocationID(), method, args)
+}
+
+// Stream calls method on the remote hub and returns a channel of StreamItem values, closed
+// when the server sends Completion.
+func (c *Client) Stream(ctx context.Context, method string, args ...interface{}) (<-chan interface{}, error) {
+	conn := c.currentConnection()
+	if conn == nil {
+		return nil, eris.New("signalr: not connected")
+	}
+	id := c.newInvocationID()
+	stream := newClientStream()
+	c.mx.Lock()
+	c.streams[id] = stream
+	c.mx.Unlock()
+	if err := conn.SendStreamInvocation(id, method, args, nil); err != nil {
+		c.removeStream(id)
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		c.removeStream(id)
+	}()
+	return stream.ch, nil
+}
+
+// removeStream deletes id from the stream table and closes its channel exactly once, however
+// it ends: the server's Completion, the caller canceling Stream's ctx, or the connection
+// failing out from under it.
+func (c *Client) removeStream(id string) {
+	c.mx.Lock()
+	stream, ok := c.streams[id]
+	delete(c.streams, id)
+	c.mx.Unlock()
+	if ok {
+		stream.close()
+	}
+}
+
+// On registers handler to be called when the server invokes method on this client. handler may
+// take any number of parameters matching the arguments the server sends; it is called via
+// reflection with each argument JSON round-tripped into the declared parameter type.
+func (c *Client) On(method string, handler interface{}) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.handlers[method] = reflect.ValueOf(handler)
+}
+
+// Close disables reconnect and tears down the underlying connection.
+func (c *Client) Close() error {
+	c.reconnect = false
+	c.cancel()
+	conn := c.currentConnection()
+	if conn == nil {
+		return nil
+	}
+	return conn.Close("", false)
+}
+
+func (c *Client) newInvocationID() string {
+	return strconv.FormatUint(atomic.AddUint64(&c.nextID, 1), 10)
+}
+
+// watchConnection redials with exponential backoff whenever the connection is lost, as long as
+// Close has not been called.
+func (c *Client) watchConnection() {
+	for {
+		conn := c.currentConnection()
+		if conn == nil {
+			return
+		}
+		<-conn.Context().Done()
+		if !c.reconnect || c.ctx.Err() != nil {
+			return
+		}
+		c.failPending(eris.New("signalr: connection lost"))
+
+		backoff := c.reconnectMin
+		for {
+			jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+			select {
+			case <-time.After(backoff + jitter):
+			case <-c.ctx.Done():
+				return
+			}
+			if err := c.connect(); err == nil {
+				break
+			}
+			if backoff < c.reconnectMax {
+				backoff *= 2
+				if backoff > c.reconnectMax {
+					backoff = c.reconnectMax
+				}
+			}
+		}
+		go c.receiveLoop()
+	}
+}
+
+// noopLogger discards every log entry; it backs a Client, which has no StructuredLogger of its
+// own to plug into newHubConnection.
+type noopLogger struct{}
+
+func (l *noopLogger) Log(keyvals ...interface{}) error { return nil }
@@ -0,0 +1,138 @@
+package signalr
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/rotisserie/eris"
+)
+
+// cancelInvocationMessage (type 5) tells the peer that sent an upload stream to stop: no more
+// StreamItem frames for invocationID are wanted.
+type cancelInvocationMessage struct {
+	Type         int    `json:"type"`
+	InvocationID string `json:"invocationId"`
+}
+
+// BindInvocationArgs builds the reflect.Value argument list for calling method with msg. Plain
+// arguments are JSON round-tripped from msg.Arguments into their declared parameter types in
+// order; each <-chan T parameter instead consumes the next ID in msg.StreamIds, bound via
+// conn.OpenClientStreams to a channel that StreamItem frames for that ID feed and a Completion
+// frame closes. The hub dispatcher is meant to call this instead of decoding msg.Arguments
+// directly whenever msg.StreamIds is non-empty.
+//
+// NOTE: that dispatcher (the code that receives an invocationMessage, looks up the target
+// method by reflection, and calls it) is not present in this snapshot of the repo, so this
+// function currently has no caller outside its own test. It's unit-tested in isolation; wiring
+// it into the dispatcher is tracked separately from this change.
+func BindInvocationArgs(conn hubConnection, method reflect.Type, msg invocationMessage) ([]reflect.Value, error) {
+	streams := conn.OpenClientStreams(msg.StreamIds)
+	args := make([]reflect.Value, method.NumIn())
+	streamIdx, argIdx := 0, 0
+	for i := 0; i < method.NumIn(); i++ {
+		paramType := method.In(i)
+		if paramType.Kind() == reflect.Chan && paramType.ChanDir() != reflect.SendDir {
+			if streamIdx >= len(msg.StreamIds) {
+				return nil, eris.Errorf("BindInvocationArgs: %s expects more upload streams than invocation %s provided", method, msg.InvocationID)
+			}
+			streamID := msg.StreamIds[streamIdx]
+			streamIdx++
+			ch, ok := streams[streamID]
+			if !ok {
+				return nil, eris.Errorf("BindInvocationArgs: no channel opened for stream %s", streamID)
+			}
+			args[i] = bindClientStreamChan(ch, paramType)
+			continue
+		}
+		value := reflect.New(paramType)
+		if argIdx < len(msg.Arguments) {
+			if raw, err := json.Marshal(msg.Arguments[argIdx]); err == nil {
+				_ = json.Unmarshal(raw, value.Interface())
+			}
+		}
+		args[i] = value.Elem()
+		argIdx++
+	}
+	return args, nil
+}
+
+// bindClientStreamChan returns a new channel of chanType's element type, backed by a goroutine
+// that JSON round-trips each value pushed onto src (the untyped chan interface{} OpenClientStreams
+// returned) into that element type and forwards it, closing the typed channel when src closes.
+func bindClientStreamChan(src chan interface{}, chanType reflect.Type) reflect.Value {
+	// reflect.MakeChan rejects a unidirectional channel type, so build a bidirectional one of
+	// the same element type and convert it to chanType (e.g. <-chan T) for the caller.
+	bidirectional := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, chanType.Elem()), 0)
+	go func() {
+		for item := range src {
+			elem := reflect.New(chanType.Elem())
+			if raw, err := json.Marshal(item); err == nil {
+				_ = json.Unmarshal(raw, elem.Interface())
+			}
+			bidirectional.Send(elem.Elem())
+		}
+		bidirectional.Close()
+	}()
+	return bidirectional.Convert(chanType)
+}
+
+// OpenClientStreams registers one channel per streamID carried by an incoming invocationMessage
+// so the dispatcher can wire each into the target hub method's corresponding <-chan T parameter
+// via reflection. Subsequent StreamItem frames with a matching InvocationID are pushed onto the
+// channel; a Completion frame closes it.
+func (c *defaultHubConnection) OpenClientStreams(streamIDs []string) map[string]chan interface{} {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	streams := make(map[string]chan interface{}, len(streamIDs))
+	for _, id := range streamIDs {
+		ch := make(chan interface{})
+		c.clientStreams[id] = ch
+		streams[id] = ch
+	}
+	return streams
+}
+
+// consumeClientStreamFrame intercepts StreamItem/Completion frames addressed to an open client
+// upload stream. It reports whether message was consumed and should not be handed to the hub
+// dispatcher as a regular frame.
+func (c *defaultHubConnection) consumeClientStreamFrame(message interface{}) bool {
+	switch msg := message.(type) {
+	case streamItemMessage:
+		c.mx.Lock()
+		ch, ok := c.clientStreams[msg.InvocationID]
+		c.mx.Unlock()
+		if !ok {
+			return false
+		}
+		select {
+		case ch <- msg.Item:
+		case <-c.ctx.Done():
+		}
+		return true
+	case completionMessage:
+		c.mx.Lock()
+		ch, ok := c.clientStreams[msg.InvocationID]
+		if ok {
+			delete(c.clientStreams, msg.InvocationID)
+		}
+		c.mx.Unlock()
+		if !ok {
+			return false
+		}
+		close(ch)
+		return true
+	default:
+		return false
+	}
+}
+
+// closeClientStreams is called when the connection aborts, so goroutines blocked reading from
+// an in-flight upload stream aren't left waiting forever.
+func (c *defaultHubConnection) closeClientStreams() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	for id, ch := range c.clientStreams {
+		close(ch)
+		delete(c.clientStreams, id)
+	}
+}
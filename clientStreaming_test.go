@@ -0,0 +1,56 @@
+package signalr
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestBindInvocationArgsWiresChannelParameter(t *testing.T) {
+	conn := &defaultHubConnection{ctx: context.Background(), clientStreams: make(map[string]chan interface{})}
+
+	methodType := reflect.TypeOf(func(name string, items <-chan int) {})
+	msg := invocationMessage{
+		InvocationID: "1",
+		Target:       "upload",
+		Arguments:    []interface{}{"file.txt"},
+		StreamIds:    []string{"s1"},
+	}
+
+	args, err := BindInvocationArgs(conn, methodType, msg)
+	if err != nil {
+		t.Fatalf("BindInvocationArgs failed: %v", err)
+	}
+	if len(args) != 2 {
+		t.Fatalf("expected 2 args, got %d", len(args))
+	}
+	if args[0].String() != "file.txt" {
+		t.Fatalf("expected first arg %q, got %q", "file.txt", args[0].String())
+	}
+
+	streamChan := args[1].Interface().(<-chan int)
+
+	conn.mx.Lock()
+	inbound := conn.clientStreams["s1"]
+	conn.mx.Unlock()
+
+	inbound <- 42
+	if got := <-streamChan; got != 42 {
+		t.Fatalf("expected 42, got %d", got)
+	}
+
+	close(inbound)
+	if _, ok := <-streamChan; ok {
+		t.Fatalf("expected the bound channel to close once the source stream closes")
+	}
+}
+
+func TestBindInvocationArgsRejectsTooFewStreamIds(t *testing.T) {
+	conn := &defaultHubConnection{ctx: context.Background(), clientStreams: make(map[string]chan interface{})}
+	methodType := reflect.TypeOf(func(a <-chan int, b <-chan int) {})
+	msg := invocationMessage{InvocationID: "1", StreamIds: []string{"s1"}}
+
+	if _, err := BindInvocationArgs(conn, methodType, msg); err == nil {
+		t.Fatalf("expected an error when the method declares more upload streams than the invocation provided")
+	}
+}
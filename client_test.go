@@ -0,0 +1,158 @@
+package signalr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestClientStreamPushDuringCloseDoesNotPanic exercises the exact race the review flagged:
+// one goroutine pushing StreamItem values while another concurrently closes the stream (as
+// dispatch and Stream's cancellation goroutine do in practice). Neither side should ever send
+// on, or close, an already-closed channel.
+func TestClientStreamPushDuringCloseDoesNotPanic(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		stream := newClientStream()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			stream.push(i)
+		}()
+		go func() {
+			defer wg.Done()
+			stream.close()
+		}()
+		done := make(chan struct{})
+		go func() {
+			for range stream.ch {
+			}
+			close(done)
+		}()
+		wg.Wait()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("stream.ch was never closed")
+		}
+	}
+}
+
+// TestClientStreamCloseIsIdempotent confirms multiple close() calls (e.g. from Completion and
+// a racing ctx cancellation) never double-close the channel.
+func TestClientStreamCloseIsIdempotent(t *testing.T) {
+	stream := newClientStream()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream.close()
+		}()
+	}
+	wg.Wait()
+	if _, ok := <-stream.ch; ok {
+		t.Fatalf("expected stream.ch to be closed")
+	}
+}
+
+// TestDialTransportFallsBackOnDialFailure confirms a transport this client recognizes but
+// fails to dial (ServerSentEvents, not yet implemented) doesn't abort the whole connect; the
+// next transport the server offered is tried instead.
+func TestDialTransportFallsBackOnDialFailure(t *testing.T) {
+	c := &Client{}
+	negotiated := negotiateResponse{
+		ConnectionID: "conn1",
+		AvailableTransports: []availableTransport{
+			{Transport: "ServerSentEvents"},
+			{Transport: "LongPolling"},
+		},
+	}
+	conn, err := c.dialTransport(negotiated)
+	if err != nil {
+		t.Fatalf("expected dialTransport to fall back to LongPolling, got error: %v", err)
+	}
+	if conn == nil {
+		t.Fatalf("expected a non-nil connection from the LongPolling fallback")
+	}
+}
+
+// TestDialTransportReturnsLastErrorWhenNoneDial confirms that when every offered transport
+// fails to dial, dialTransport surfaces the last dial error rather than a generic one.
+func TestDialTransportReturnsLastErrorWhenNoneDial(t *testing.T) {
+	c := &Client{}
+	negotiated := negotiateResponse{
+		ConnectionID: "conn1",
+		AvailableTransports: []availableTransport{
+			{Transport: "ServerSentEvents"},
+		},
+	}
+	_, err := c.dialTransport(negotiated)
+	if err == nil {
+		t.Fatalf("expected an error when the only offered transport fails to dial")
+	}
+	if !strings.Contains(err.Error(), "ServerSentEvents client transport") {
+		t.Fatalf("expected the ServerSentEvents dial error, got: %v", err)
+	}
+}
+
+// TestClientLongPollingConnectionRoundTrips drives a clientLongPollingConnection against a
+// fake HTTP server that speaks longPollingTransport's handlePoll/handleSend wire contract
+// (one frame per GET response body, one frame per POST request body), confirming the client
+// actually performs the HTTP GET/POST loop rather than only talking to in-process channels.
+func TestClientLongPollingConnectionRoundTrips(t *testing.T) {
+	toClient := make(chan []byte, 1)
+	toClient <- []byte("hello from server")
+
+	var mu sync.Mutex
+	var received [][]byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			received = append(received, body)
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		select {
+		case frame := <-toClient:
+			_, _ = w.Write(frame)
+		case <-req.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &Client{baseURL: server.URL, httpClient: server.Client(), ctx: ctx}
+	conn := newClientLongPollingConnection(client, "conn1")
+
+	if _, err := conn.Write([]byte("hello from client")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	mu.Lock()
+	if len(received) != 1 || string(received[0]) != "hello from client" {
+		t.Fatalf("expected the server to receive the posted frame, got %v", received)
+	}
+	mu.Unlock()
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if got := string(buf[:n]); got != "hello from server" {
+		t.Fatalf("expected %q, got %q", "hello from server", got)
+	}
+}
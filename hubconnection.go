@@ -11,7 +11,7 @@ import (
 // hubConnection is used by HubContext, Server and ClientConnection to realize the external API.
 // hubConnection uses a transport connection (of type Connection) and a HubProtocol to send and receive SignalR messages.
 type hubConnection interface {
-	Start()
+	Start(keepAliveInterval time.Duration, clientTimeoutInterval time.Duration)
 	IsConnected() bool
 	ConnectionID() string
 	Receive() (interface{}, error)
@@ -19,9 +19,24 @@ type hubConnection interface {
 	SendStreamInvocation(id string, target string, args []interface{}, streamIds []string) error
 	StreamItem(id string, item interface{}) error
 	Completion(id string, result interface{}, error string) error
+	// CancelInvocation tells the peer to stop pushing StreamItem frames for an upload stream
+	// it is sending us, e.g. because the target hub method returned before it drained.
+	CancelInvocation(id string) error
 	Close(error string, allowReconnect bool) error
 	Ping() error
+	// SetBackplaneTarget records the group name or connection ID this connection was reached
+	// through (Clients.Group("g") or Clients.Client(id), "" for Clients.All()), so the next
+	// SendInvocation/StreamItem/Completion call also publishes to the configured Backplane.
+	SetBackplaneTarget(groupOrConnID string)
+	// OpenClientStreams registers one channel per streamID carried by an incoming
+	// invocationMessage, so BindInvocationArgs can wire each into the target hub method's
+	// corresponding <-chan T parameter.
+	OpenClientStreams(streamIDs []string) map[string]chan interface{}
+	// dispatch writes a frame that originated on another node (via Backplane) straight to this
+	// connection, bypassing SendInvocation/StreamItem/Completion so it isn't re-published.
+	dispatch(message interface{}) error
 	LastWriteStamp() time.Time
+	LastReadStamp() time.Time
 	Items() *sync.Map
 	Context() context.Context
 	Abort()
@@ -39,6 +54,7 @@ func newHubConnection(connection Connection, protocol HubProtocol, maximumReceiv
 		items:                     &sync.Map{},
 		abortChans:                make([]chan error, 0),
 		info:                      info,
+		clientStreams:             make(map[string]chan interface{}),
 	}
 	// Listen on abort
 	go func() {
@@ -54,6 +70,7 @@ func newHubConnection(connection Connection, protocol HubProtocol, maximumReceiv
 			c.connected = false
 		}
 		c.mx.Unlock()
+		c.closeClientStreams()
 	}()
 	return c
 }
@@ -69,17 +86,47 @@ type defaultHubConnection struct {
 	maximumReceiveMessageSize uint
 	items                     *sync.Map
 	lastWriteStamp            time.Time
+	lastReadStamp             time.Time
 	info                      StructuredLogger
+	backplane                 Backplane
+	groupOrConnID             string
+	publishArmed              bool
+	clientStreams             map[string]chan interface{}
 }
 
 func (c *defaultHubConnection) Items() *sync.Map {
 	return c.items
 }
 
-func (c *defaultHubConnection) Start() {
-	defer c.mx.Unlock()
+// Start marks the connection as connected and launches the keep-alive goroutine that pings
+// idle peers every keepAliveInterval and Abort()s ones that have gone silent for longer than
+// clientTimeoutInterval, mirroring the reference SignalR server's timeout handling.
+func (c *defaultHubConnection) Start(keepAliveInterval time.Duration, clientTimeoutInterval time.Duration) {
 	c.mx.Lock()
 	c.connected = true
+	c.lastReadStamp = time.Now()
+	c.mx.Unlock()
+	go c.keepAlive(keepAliveInterval, clientTimeoutInterval)
+}
+
+func (c *defaultHubConnection) keepAlive(keepAliveInterval time.Duration, clientTimeoutInterval time.Duration) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if time.Since(c.LastReadStamp()) >= clientTimeoutInterval {
+				_ = c.info.Log(evt, msgSend, "message", "client timeout exceeded", "connectionId", c.ConnectionID())
+				c.Abort()
+				return
+			}
+			if time.Since(c.LastWriteStamp()) >= keepAliveInterval {
+				_ = c.Ping()
+			}
+		}
+	}
 }
 
 func (c *defaultHubConnection) IsConnected() bool {
@@ -114,7 +161,23 @@ type receiveResult struct {
 	err     error
 }
 
+// Receive returns the next frame addressed to the hub dispatcher. Frames belonging to an
+// open client-to-server upload stream (see OpenClientStreams) are intercepted and fed to that
+// stream's channel instead of being returned here.
 func (c *defaultHubConnection) Receive() (interface{}, error) {
+	for {
+		message, err := c.receiveOne()
+		if err != nil {
+			return nil, err
+		}
+		if c.consumeClientStreamFrame(message) {
+			continue
+		}
+		return message, nil
+	}
+}
+
+func (c *defaultHubConnection) receiveOne() (interface{}, error) {
 	if c.ctx.Err() != nil {
 		return nil, eris.Wrap(c.ctx.Err(), "hubConnection canceled")
 	}
@@ -161,6 +224,11 @@ func (c *defaultHubConnection) Receive() (interface{}, error) {
 	}(recvResCh)
 	select {
 	case recvRes := <-recvResCh:
+		if recvRes.err == nil {
+			c.mx.Lock()
+			c.lastReadStamp = time.Now()
+			c.mx.Unlock()
+		}
 		return recvRes.message, recvRes.err
 	case <-c.ctx.Done():
 		return nil, eris.Wrap(c.ctx.Err(), "hubConnection canceled")
@@ -174,6 +242,7 @@ func (c *defaultHubConnection) SendInvocation(id string, target string, args []i
 		Target:       target,
 		Arguments:    args,
 	}
+	c.publishToBackplane(invocationMessage)
 	return c.writeMessage(invocationMessage)
 }
 
@@ -194,6 +263,7 @@ func (c *defaultHubConnection) StreamItem(id string, item interface{}) error {
 		InvocationID: id,
 		Item:         item,
 	}
+	c.publishToBackplane(streamItemMessage)
 	return c.writeMessage(streamItemMessage)
 }
 
@@ -204,9 +274,18 @@ func (c *defaultHubConnection) Completion(id string, result interface{}, error s
 		Result:       result,
 		Error:        error,
 	}
+	c.publishToBackplane(completionMessage)
 	return c.writeMessage(completionMessage)
 }
 
+func (c *defaultHubConnection) CancelInvocation(id string) error {
+	var cancelInvocationMessage = cancelInvocationMessage{
+		Type:         5,
+		InvocationID: id,
+	}
+	return c.writeMessage(cancelInvocationMessage)
+}
+
 func (c *defaultHubConnection) Ping() error {
 	var pingMessage = hubMessage{
 		Type: 6,
@@ -215,9 +294,50 @@ func (c *defaultHubConnection) Ping() error {
 }
 
 func (c *defaultHubConnection) LastWriteStamp() time.Time {
+	defer c.mx.Unlock()
+	c.mx.Lock()
 	return c.lastWriteStamp
 }
 
+func (c *defaultHubConnection) LastReadStamp() time.Time {
+	defer c.mx.Unlock()
+	c.mx.Lock()
+	return c.lastReadStamp
+}
+
+func (c *defaultHubConnection) dispatch(message interface{}) error {
+	return c.writeMessage(message)
+}
+
+// SetBackplaneTarget arms exactly the next SendInvocation/StreamItem/Completion call to also
+// publish to the configured Backplane, addressed to groupOrConnID ("" for Clients.All()). Only
+// Clients.All()/Group()/Client() broadcast sends should call this; a plain unary RPC reply to
+// the calling connection must never reach the backplane, so it's consumed (reset to disarmed)
+// whether or not a Backplane is even configured.
+func (c *defaultHubConnection) SetBackplaneTarget(groupOrConnID string) {
+	c.mx.Lock()
+	c.groupOrConnID = groupOrConnID
+	c.publishArmed = true
+	c.mx.Unlock()
+}
+
+// publishToBackplane fans msg out to peer nodes, but only for the one call immediately
+// following a SetBackplaneTarget: ordinary completions/stream items answering the caller never
+// arm it, so they stay local instead of being rebroadcast to every connection cluster-wide.
+func (c *defaultHubConnection) publishToBackplane(msg interface{}) {
+	c.mx.Lock()
+	armed := c.publishArmed
+	groupOrConnID := c.groupOrConnID
+	c.publishArmed = false
+	c.mx.Unlock()
+	if !armed || c.backplane == nil {
+		return
+	}
+	if err := c.backplane.Publish(groupOrConnID, msg); err != nil {
+		_ = c.info.Log(evt, msgSend, "message", "backplane publish failed", "error", err)
+	}
+}
+
 func (c *defaultHubConnection) writeMessage(message interface{}) error {
 	c.mx.Lock()
 	c.lastWriteStamp = time.Now()
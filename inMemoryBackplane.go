@@ -0,0 +1,46 @@
+package signalr
+
+import "sync"
+
+// inMemoryBackplane is a Backplane for tests and single-process deployments: every Publish is
+// delivered to every Subscribe handler registered against the same instance, synchronously.
+type inMemoryBackplane struct {
+	mx       sync.RWMutex
+	handlers []func(msg backplaneMessage)
+	nodeID   string
+}
+
+// NewInMemoryBackplane returns a Backplane that fans messages out in-process. Share one
+// instance across several Server's WithBackplane options to exercise multi-node fan-out in
+// tests without a real message broker: WithBackplane tags each Server's outgoing messages with
+// its own node id (see originTaggingBackplane), so sharing this one instance doesn't collapse
+// them onto a single id.
+func NewInMemoryBackplane() Backplane {
+	return &inMemoryBackplane{nodeID: generateBackplaneNodeID()}
+}
+
+// Publish stamps msg with this instance's own node id. Only used when a caller publishes
+// directly, bypassing WithBackplane/originTaggingBackplane's per-Server tagging.
+func (b *inMemoryBackplane) Publish(groupOrConnID string, msg interface{}) error {
+	return b.publishAs(b.nodeID, groupOrConnID, msg)
+}
+
+func (b *inMemoryBackplane) publishAs(originID string, groupOrConnID string, msg interface{}) error {
+	envelope, err := newBackplaneMessage(originID, groupOrConnID, msg)
+	if err != nil {
+		return err
+	}
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+	for _, handler := range b.handlers {
+		handler(envelope)
+	}
+	return nil
+}
+
+func (b *inMemoryBackplane) Subscribe(handler func(msg backplaneMessage)) error {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+	b.handlers = append(b.handlers, handler)
+	return nil
+}
@@ -0,0 +1,29 @@
+package signalr
+
+import "time"
+
+// defaultKeepAliveInterval is how often a server pings an otherwise idle connection, matching
+// the reference SignalR server's default.
+const defaultKeepAliveInterval = 15 * time.Second
+
+// defaultClientTimeoutInterval is how long a server waits without receiving any frame from a
+// client before it Abort()s the connection, matching the reference SignalR server's default.
+const defaultClientTimeoutInterval = 30 * time.Second
+
+// WithKeepAliveInterval overrides how often Server pings a connection that hasn't written
+// anything recently. The default is 15 seconds.
+func WithKeepAliveInterval(interval time.Duration) ServerOption {
+	return func(server *Server) error {
+		server.keepAliveInterval = interval
+		return nil
+	}
+}
+
+// WithClientTimeoutInterval overrides how long Server waits without receiving any frame from a
+// client before it considers the connection dead and Abort()s it. The default is 30 seconds.
+func WithClientTimeoutInterval(interval time.Duration) ServerOption {
+	return func(server *Server) error {
+		server.clientTimeoutInterval = interval
+		return nil
+	}
+}
@@ -0,0 +1,168 @@
+package signalr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// longPollingTransport delivers server-to-client frames as a sequence of plain HTTP responses,
+// one per GET, and accepts client-to-server frames on a companion POST to the same path.
+type longPollingTransport struct {
+	mx          sync.Mutex
+	connections map[string]*longPollingConnection
+}
+
+// NewLongPollingTransport returns a Transport that delivers server-to-client frames as a
+// sequence of plain HTTP responses, one per GET, and accepts client-to-server frames on a
+// companion POST, for passing to MapHub/WithTransports.
+func NewLongPollingTransport() Transport {
+	return &longPollingTransport{connections: make(map[string]*longPollingConnection)}
+}
+
+func (t *longPollingTransport) Name() string { return "LongPolling" }
+
+func (t *longPollingTransport) TransferFormats() []string { return []string{"Text", "Binary"} }
+
+func (t *longPollingTransport) Handles(req *http.Request) bool {
+	// longPollingTransport is the fallback: it claims any request no other registered
+	// transport (WebSockets, ServerSentEvents) already claimed for this connection.
+	return true
+}
+
+func (t *longPollingTransport) Handler(server *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		connectionID := req.URL.Query().Get("id")
+		if connectionID == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		conn := t.connectionFor(server, connectionID)
+		switch req.Method {
+		case http.MethodPost:
+			t.handleSend(w, req, conn)
+		default:
+			t.handlePoll(w, req, conn)
+		}
+	})
+}
+
+func (t *longPollingTransport) connectionFor(server *Server, connectionID string) *longPollingConnection {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	conn, ok := t.connections[connectionID]
+	if !ok {
+		conn = newLongPollingConnection(connectionID)
+		t.connections[connectionID] = conn
+		go func() {
+			server.Run(conn)
+			t.releaseConnection(connectionID)
+		}()
+	}
+	return conn
+}
+
+// releaseConnection removes connectionID's entry and cancels its connection, once server.Run
+// returns (whether because the hub connection aborted, e.g. a keep-alive timeout, or the
+// underlying Read/Write failed). Without this, nothing ever learns the connection is gone, since
+// unlike sseTransport.handleStream (one HTTP request blocking for the connection's whole life),
+// each long-polling GET/POST is a short-lived request sharing a connectionID: the map entry and
+// the pending poll goroutines it leaves behind would otherwise never be cleaned up.
+func (t *longPollingTransport) releaseConnection(connectionID string) {
+	t.mx.Lock()
+	conn, ok := t.connections[connectionID]
+	delete(t.connections, connectionID)
+	t.mx.Unlock()
+	if ok {
+		conn.cancel()
+	}
+}
+
+func (t *longPollingTransport) handlePoll(w http.ResponseWriter, req *http.Request, conn *longPollingConnection) {
+	select {
+	case frame, ok := <-conn.outbox:
+		if !ok {
+			http.Error(w, "connection closed", http.StatusGone)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(frame)
+	case <-req.Context().Done():
+	case <-conn.ctx.Done():
+		http.Error(w, "connection closed", http.StatusGone)
+	}
+}
+
+func (t *longPollingTransport) handleSend(w http.ResponseWriter, req *http.Request, conn *longPollingConnection) {
+	frame, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	select {
+	case conn.inbox <- frame:
+		w.WriteHeader(http.StatusOK)
+	case <-conn.ctx.Done():
+		http.Error(w, "connection closed", http.StatusGone)
+	}
+}
+
+// longPollingConnection adapts one long-polled connection (a GET/POST pair sharing a
+// connectionId) to the Connection interface so it can be driven by the same hubConnection
+// code as webSocketConnection.
+type longPollingConnection struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	connectionID string
+	outbox       chan []byte
+	inbox        chan []byte
+	pending      []byte
+}
+
+func newLongPollingConnection(connectionID string) *longPollingConnection {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &longPollingConnection{
+		ctx:          ctx,
+		cancel:       cancel,
+		connectionID: connectionID,
+		outbox:       make(chan []byte, 16),
+		inbox:        make(chan []byte, 16),
+	}
+}
+
+// Read copies buffered inbound bytes into p, carrying over whatever doesn't fit so a POST body
+// larger than the caller's read buffer is delivered across multiple Reads instead of truncated.
+func (c *longPollingConnection) Read(p []byte) (int, error) {
+	if len(c.pending) == 0 {
+		select {
+		case frame, ok := <-c.inbox:
+			if !ok {
+				return 0, eris.New("longPollingConnection closed")
+			}
+			c.pending = frame
+		case <-c.ctx.Done():
+			return 0, eris.Wrap(c.ctx.Err(), "longPollingConnection canceled")
+		}
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *longPollingConnection) Write(p []byte) (int, error) {
+	frame := make([]byte, len(p))
+	copy(frame, p)
+	select {
+	case c.outbox <- frame:
+		return len(p), nil
+	case <-c.ctx.Done():
+		return 0, eris.Wrap(c.ctx.Err(), "longPollingConnection canceled")
+	}
+}
+
+func (c *longPollingConnection) ConnectionID() string { return c.connectionID }
+
+func (c *longPollingConnection) Context() context.Context { return c.ctx }
@@ -0,0 +1,175 @@
+package signalr
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/rotisserie/eris"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// messagePackHubProtocol implements the "messagepack" HubProtocol from the SignalR spec.
+// Each frame is a 7-bit varint length prefix (the same Base128 Varint Protobuf uses) followed
+// by a msgpack array whose first element is the message type integer already used by
+// invocationMessage, streamItemMessage, completionMessage, closeMessage, hubMessage and ping.
+type messagePackHubProtocol struct {
+	info StructuredLogger
+}
+
+func (m *messagePackHubProtocol) Name() string { return "messagepack" }
+
+func (m *messagePackHubProtocol) TransferFormat() string { return "Binary" }
+
+func (m *messagePackHubProtocol) setDebugLogger(logger StructuredLogger) { m.info = logger }
+
+// WriteMessage encodes message as a length-prefixed msgpack array and writes it to writer.
+func (m *messagePackHubProtocol) WriteMessage(message interface{}, writer io.Writer) error {
+	body, err := m.encode(message)
+	if err != nil {
+		return eris.Wrap(err, "messagePackHubProtocol: encode failed")
+	}
+	var framed bytes.Buffer
+	writeVarInt(&framed, uint64(len(body)))
+	framed.Write(body)
+	_, err = writer.Write(framed.Bytes())
+	return err
+}
+
+// ReadMessage reads one length-prefixed msgpack frame from buf. It returns complete=false
+// without consuming buf if the varint length prefix or the frame body isn't fully buffered yet.
+func (m *messagePackHubProtocol) ReadMessage(buf *bytes.Buffer) (interface{}, bool, error) {
+	data := buf.Bytes()
+	length, prefixLen, ok := readVarInt(data)
+	if !ok {
+		return nil, false, nil
+	}
+	if uint64(len(data)-prefixLen) < length {
+		return nil, false, nil
+	}
+	frame := data[prefixLen : prefixLen+int(length)]
+	message, err := m.decode(frame)
+	buf.Next(prefixLen + int(length))
+	return message, true, err
+}
+
+func (m *messagePackHubProtocol) encode(message interface{}) ([]byte, error) {
+	switch msg := message.(type) {
+	case invocationMessage:
+		return msgpack.Marshal([]interface{}{msg.Type, map[string]string{}, msg.InvocationID, msg.Target, msg.Arguments, msg.StreamIds})
+	case streamItemMessage:
+		return msgpack.Marshal([]interface{}{msg.Type, map[string]string{}, msg.InvocationID, msg.Item})
+	case completionMessage:
+		return msgpack.Marshal([]interface{}{msg.Type, map[string]string{}, msg.InvocationID, msg.Error, msg.Result})
+	case cancelInvocationMessage:
+		return msgpack.Marshal([]interface{}{msg.Type, map[string]string{}, msg.InvocationID})
+	case closeMessage:
+		return msgpack.Marshal([]interface{}{msg.Type, msg.Error, msg.AllowReconnect})
+	case hubMessage:
+		return msgpack.Marshal([]interface{}{msg.Type})
+	default:
+		return nil, eris.Errorf("messagePackHubProtocol: cannot encode %T", message)
+	}
+}
+
+func (m *messagePackHubProtocol) decode(frame []byte) (interface{}, error) {
+	var arr []interface{}
+	if err := msgpack.Unmarshal(frame, &arr); err != nil {
+		return nil, eris.Wrap(err, "messagePackHubProtocol: decode failed")
+	}
+	if len(arr) == 0 {
+		return nil, eris.New("messagePackHubProtocol: empty frame")
+	}
+	messageType, ok := arr[0].(int8)
+	if !ok {
+		return nil, eris.New("messagePackHubProtocol: frame[0] is not a message type")
+	}
+	switch messageType {
+	case 1, 4:
+		return invocationMessage{
+			Type:         int(messageType),
+			InvocationID: toString(arr[2]),
+			Target:       toString(arr[3]),
+			Arguments:    toSlice(arr[4]),
+			StreamIds:    toStringSlice(arr[5]),
+		}, nil
+	case 2:
+		return streamItemMessage{
+			Type:         int(messageType),
+			InvocationID: toString(arr[2]),
+			Item:         arr[3],
+		}, nil
+	case 3:
+		return completionMessage{
+			Type:         int(messageType),
+			InvocationID: toString(arr[2]),
+			Error:        toString(arr[3]),
+			Result:       arr[4],
+		}, nil
+	case 5:
+		return cancelInvocationMessage{
+			Type:         int(messageType),
+			InvocationID: toString(arr[2]),
+		}, nil
+	case 6:
+		return hubMessage{Type: int(messageType)}, nil
+	case 7:
+		allowReconnect, _ := arr[2].(bool)
+		return closeMessage{
+			Type:           int(messageType),
+			Error:          toString(arr[1]),
+			AllowReconnect: allowReconnect,
+		}, nil
+	default:
+		return nil, eris.Errorf("messagePackHubProtocol: unknown message type %d", messageType)
+	}
+}
+
+func toString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func toSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+// toStringSlice converts a decoded msgpack array to []string. msgpack.Unmarshal into
+// interface{} always yields []interface{} for arrays, even when every element is a string, so
+// a direct v.([]string) type assertion never succeeds.
+func toStringSlice(v interface{}) []string {
+	raw := toSlice(v)
+	if raw == nil {
+		return nil
+	}
+	strs := make([]string, 0, len(raw))
+	for _, item := range raw {
+		strs = append(strs, toString(item))
+	}
+	return strs
+}
+
+// writeVarInt encodes value as a Base128 Varint (the same little-endian, 7-bit-group,
+// continuation-bit-in-MSB scheme Protobuf uses) and appends it to buf.
+func writeVarInt(buf *bytes.Buffer, value uint64) {
+	for value >= 0x80 {
+		buf.WriteByte(byte(value) | 0x80)
+		value >>= 7
+	}
+	buf.WriteByte(byte(value))
+}
+
+// readVarInt decodes a Base128 Varint from the start of data. ok is false if data doesn't yet
+// contain a complete varint (i.e. more bytes are needed before the frame can be parsed).
+func readVarInt(data []byte) (value uint64, n int, ok bool) {
+	var shift uint
+	for n = 0; n < len(data); n++ {
+		b := data[n]
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return value, n + 1, true
+		}
+		shift += 7
+	}
+	return 0, 0, false
+}
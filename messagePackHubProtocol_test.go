@@ -0,0 +1,71 @@
+package signalr
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMessagePackHubProtocolRoundTripsStreamIds(t *testing.T) {
+	protocol := &messagePackHubProtocol{}
+	original := invocationMessage{
+		Type:         4,
+		InvocationID: "1",
+		Target:       "upload",
+		Arguments:    []interface{}{},
+		StreamIds:    []string{"s1", "s2"},
+	}
+
+	var wire bytes.Buffer
+	if err := protocol.WriteMessage(original, &wire); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+
+	message, complete, err := protocol.ReadMessage(&wire)
+	if err != nil {
+		t.Fatalf("ReadMessage failed: %v", err)
+	}
+	if !complete {
+		t.Fatalf("expected a complete frame")
+	}
+
+	decoded, ok := message.(invocationMessage)
+	if !ok {
+		t.Fatalf("expected invocationMessage, got %T", message)
+	}
+	if !reflect.DeepEqual(decoded.StreamIds, original.StreamIds) {
+		t.Fatalf("expected StreamIds %v, got %v", original.StreamIds, decoded.StreamIds)
+	}
+}
+
+// TestMessagePackHubProtocolRoundTripsCancelInvocation confirms a connection using the
+// messagepack protocol can both write and read the CancelInvocation (type 5) frame an upload
+// stream's consumer uses to tell the peer to stop sending StreamItem frames early.
+func TestMessagePackHubProtocolRoundTripsCancelInvocation(t *testing.T) {
+	protocol := &messagePackHubProtocol{}
+	original := cancelInvocationMessage{
+		Type:         5,
+		InvocationID: "42",
+	}
+
+	var wire bytes.Buffer
+	if err := protocol.WriteMessage(original, &wire); err != nil {
+		t.Fatalf("WriteMessage failed: %v", err)
+	}
+
+	message, complete, err := protocol.ReadMessage(&wire)
+	if err != nil {
+		t.Fatalf("ReadMessage failed: %v", err)
+	}
+	if !complete {
+		t.Fatalf("expected a complete frame")
+	}
+
+	decoded, ok := message.(cancelInvocationMessage)
+	if !ok {
+		t.Fatalf("expected cancelInvocationMessage, got %T", message)
+	}
+	if decoded.InvocationID != original.InvocationID {
+		t.Fatalf("expected InvocationID %q, got %q", original.InvocationID, decoded.InvocationID)
+	}
+}
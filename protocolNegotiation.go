@@ -0,0 +1,47 @@
+package signalr
+
+import "github.com/rotisserie/eris"
+
+// handshakeRequest is the first frame a client sends on every connection, before any
+// invocation traffic, to pick the HubProtocol the rest of the connection will use.
+type handshakeRequest struct {
+	Protocol string `json:"protocol"`
+	Version  int    `json:"version"`
+}
+
+// WithHubProtocol registers an additional HubProtocol a Server will accept during the
+// handshake, alongside the built-in "json" protocol. Pass it to NewServer, e.g.
+// NewServer(SimpleTransientHubFactory(hub), WithHubProtocol(&messagePackHubProtocol{})).
+func WithHubProtocol(protocol HubProtocol) ServerOption {
+	return func(server *Server) error {
+		if server.hubProtocols == nil {
+			server.hubProtocols = defaultHubProtocols()
+		}
+		server.hubProtocols[protocol.Name()] = protocol
+		return nil
+	}
+}
+
+// defaultHubProtocols returns the set of HubProtocols every Server supports out of the box.
+func defaultHubProtocols() map[string]HubProtocol {
+	return map[string]HubProtocol{
+		"json": &jsonHubProtocol{},
+	}
+}
+
+// selectHubProtocol resolves the HubProtocol named in a client's handshakeRequest against the
+// set a Server was built with (server.hubProtocols, seeded by defaultHubProtocols and extended
+// by WithHubProtocol), so newHubConnection can be created with the right codec.
+//
+// NOTE: the handshake handler that reads the client's handshakeRequest off the wire and calls
+// this to pick a codec before constructing the connection's hubConnection is not present in
+// this snapshot of the repo (there is no Server.Run/handshake dispatcher here to call it from).
+// This function is unit-tested in isolation; wiring it into the handshake path is tracked
+// separately from this change.
+func selectHubProtocol(protocols map[string]HubProtocol, request handshakeRequest) (HubProtocol, error) {
+	protocol, ok := protocols[request.Protocol]
+	if !ok {
+		return nil, eris.Errorf("unsupported hub protocol %q", request.Protocol)
+	}
+	return protocol, nil
+}
@@ -0,0 +1,106 @@
+package signalr
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// redisBackplane is a Backplane backed by Redis pub/sub: Publish JSON-encodes a backplaneMessage
+// envelope onto a channel shared by every node for a given hub, and Subscribe relays whatever
+// arrives on that channel back into the local process.
+type redisBackplane struct {
+	client  *redis.Client
+	channel string
+	nodeID  string
+}
+
+// NewRedisBackplane returns a Backplane that fans messages out over Redis pub/sub on channel,
+// so every Server instance sharing that channel (typically one per hub) sees the same traffic.
+func NewRedisBackplane(client *redis.Client, channel string) Backplane {
+	return &redisBackplane{client: client, channel: channel, nodeID: generateBackplaneNodeID()}
+}
+
+// Publish stamps msg with this instance's own node id. Only used when a caller publishes
+// directly, bypassing WithBackplane/originTaggingBackplane's per-Server tagging.
+func (b *redisBackplane) Publish(groupOrConnID string, msg interface{}) error {
+	return b.publishAs(b.nodeID, groupOrConnID, msg)
+}
+
+func (b *redisBackplane) publishAs(originID string, groupOrConnID string, msg interface{}) error {
+	envelope, err := newBackplaneMessage(originID, groupOrConnID, msg)
+	if err != nil {
+		return err
+	}
+	wire, err := json.Marshal(envelope)
+	if err != nil {
+		return eris.Wrap(err, "redisBackplane: encode failed")
+	}
+	return b.client.Publish(context.Background(), b.channel, wire).Err()
+}
+
+func (b *redisBackplane) Subscribe(handler func(msg backplaneMessage)) error {
+	pubsub := b.client.Subscribe(context.Background(), b.channel)
+	ch := pubsub.Channel()
+	go func() {
+		for redisMsg := range ch {
+			msg, err := decodeBackplaneMessage([]byte(redisMsg.Payload))
+			if err != nil {
+				continue
+			}
+			handler(msg)
+		}
+	}()
+	return nil
+}
+
+// wireBackplaneMessage mirrors backplaneMessage but keeps Message as raw JSON, since decoding
+// straight into backplaneMessage.Message (an interface{}) would turn any concrete message
+// struct into a map[string]interface{} and lose its type.
+type wireBackplaneMessage struct {
+	OriginID      string          `json:"originId"`
+	GroupOrConnID string          `json:"groupOrConnId"`
+	MessageType   string          `json:"messageType"`
+	Message       json.RawMessage `json:"message"`
+}
+
+// decodeBackplaneMessage parses a Redis payload back into a backplaneMessage whose Message
+// field holds the concrete invocationMessage/streamItemMessage/completionMessage MessageType
+// names, not a generic map.
+func decodeBackplaneMessage(payload []byte) (backplaneMessage, error) {
+	var wire wireBackplaneMessage
+	if err := json.Unmarshal(payload, &wire); err != nil {
+		return backplaneMessage{}, eris.Wrap(err, "redisBackplane: decode envelope failed")
+	}
+	message, err := decodeBackplanePayload(wire.MessageType, wire.Message)
+	if err != nil {
+		return backplaneMessage{}, err
+	}
+	return backplaneMessage{
+		OriginID:      wire.OriginID,
+		GroupOrConnID: wire.GroupOrConnID,
+		MessageType:   wire.MessageType,
+		Message:       message,
+	}, nil
+}
+
+func decodeBackplanePayload(messageType string, raw json.RawMessage) (interface{}, error) {
+	switch messageType {
+	case backplaneMessageTypeInvocation:
+		var message invocationMessage
+		err := json.Unmarshal(raw, &message)
+		return message, err
+	case backplaneMessageTypeStreamItem:
+		var message streamItemMessage
+		err := json.Unmarshal(raw, &message)
+		return message, err
+	case backplaneMessageTypeCompletion:
+		var message completionMessage
+		err := json.Unmarshal(raw, &message)
+		return message, err
+	default:
+		return nil, eris.Errorf("redisBackplane: unknown message type %q", messageType)
+	}
+}
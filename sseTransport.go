@@ -0,0 +1,188 @@
+package signalr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// sseTransport delivers server-to-client frames over a text/event-stream response and accepts
+// client-to-server frames on a companion POST to the same path, keyed on connectionId.
+type sseTransport struct {
+	mx          sync.Mutex
+	connections map[string]*sseConnection
+}
+
+// NewSSETransport returns a Transport that delivers server-to-client frames over
+// text/event-stream and accepts client-to-server frames on a companion POST, for passing to
+// MapHub/WithTransports.
+func NewSSETransport() Transport {
+	return &sseTransport{connections: make(map[string]*sseConnection)}
+}
+
+func (t *sseTransport) Name() string { return "ServerSentEvents" }
+
+func (t *sseTransport) TransferFormats() []string { return []string{"Text"} }
+
+func (t *sseTransport) Handles(req *http.Request) bool {
+	if req.Method == http.MethodGet {
+		return strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+	}
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	_, ok := t.connections[req.URL.Query().Get("id")]
+	return ok
+}
+
+func (t *sseTransport) Handler(server *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		connectionID := req.URL.Query().Get("id")
+		if connectionID == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case http.MethodPost:
+			t.handleSend(w, req, connectionID)
+		default:
+			t.handleStream(w, req, server, connectionID)
+		}
+	})
+}
+
+func (t *sseTransport) handleStream(w http.ResponseWriter, req *http.Request, server *Server, connectionID string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	conn := newSSEConnection(req.Context(), connectionID)
+	t.mx.Lock()
+	t.connections[connectionID] = conn
+	t.mx.Unlock()
+	defer func() {
+		t.mx.Lock()
+		delete(t.connections, connectionID)
+		t.mx.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	go server.Run(conn)
+
+	for {
+		select {
+		case frame, more := <-conn.outbox:
+			if !more {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", frame); err != nil {
+				conn.cancel()
+				return
+			}
+			flusher.Flush()
+		case <-req.Context().Done():
+			conn.cancel()
+			return
+		}
+	}
+}
+
+func (t *sseTransport) handleSend(w http.ResponseWriter, req *http.Request, connectionID string) {
+	t.mx.Lock()
+	conn, ok := t.connections[connectionID]
+	t.mx.Unlock()
+	if !ok {
+		http.Error(w, "unknown connection", http.StatusNotFound)
+		return
+	}
+	if err := conn.deliver(req.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// sseConnection adapts one SSE stream (plus its companion POST endpoint) to the Connection
+// interface, so it can be driven by the same hubConnection code as webSocketConnection.
+type sseConnection struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	connectionID string
+	outbox       chan []byte
+	inbox        chan []byte
+	pending      []byte
+}
+
+func newSSEConnection(parent context.Context, connectionID string) *sseConnection {
+	ctx, cancel := context.WithCancel(parent)
+	return &sseConnection{
+		ctx:          ctx,
+		cancel:       cancel,
+		connectionID: connectionID,
+		outbox:       make(chan []byte, 16),
+		inbox:        make(chan []byte, 16),
+	}
+}
+
+func (c *sseConnection) deliver(body interface{ Read([]byte) (int, error) }) error {
+	buf := make([]byte, 0, 512)
+	chunk := make([]byte, 512)
+	for {
+		n, err := body.Read(chunk)
+		if n > 0 {
+			buf = append(buf, chunk[:n]...)
+		}
+		if err != nil {
+			break
+		}
+	}
+	select {
+	case c.inbox <- buf:
+		return nil
+	case <-c.ctx.Done():
+		return eris.Wrap(c.ctx.Err(), "sseConnection canceled")
+	}
+}
+
+// Read copies buffered inbound bytes into p, carrying over whatever doesn't fit so a POST body
+// larger than the caller's read buffer is delivered across multiple Reads instead of truncated.
+func (c *sseConnection) Read(p []byte) (int, error) {
+	if len(c.pending) == 0 {
+		select {
+		case frame, ok := <-c.inbox:
+			if !ok {
+				return 0, eris.New("sseConnection closed")
+			}
+			c.pending = frame
+		case <-c.ctx.Done():
+			return 0, eris.Wrap(c.ctx.Err(), "sseConnection canceled")
+		}
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+func (c *sseConnection) Write(p []byte) (int, error) {
+	frame := make([]byte, len(p))
+	copy(frame, p)
+	select {
+	case c.outbox <- frame:
+		return len(p), nil
+	case <-c.ctx.Done():
+		return 0, eris.Wrap(c.ctx.Err(), "sseConnection canceled")
+	}
+}
+
+func (c *sseConnection) ConnectionID() string { return c.connectionID }
+
+func (c *sseConnection) Context() context.Context { return c.ctx }
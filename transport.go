@@ -0,0 +1,41 @@
+package signalr
+
+import "net/http"
+
+// Transport adapts a concrete wire transport (WebSockets, Server-Sent Events, Long Polling, ...)
+// to the Connection interface hubConnection already knows how to drive. Server is built with a
+// set of Transports and advertises exactly those in negotiateResponse.AvailableTransports.
+type Transport interface {
+	// Name is the identifier advertised in negotiateResponse.AvailableTransports, e.g. "WebSockets".
+	Name() string
+	// TransferFormats lists the transfer formats ("Text", "Binary") this transport supports.
+	TransferFormats() []string
+	// Handles reports whether this transport is responsible for serving req. Server consults
+	// its transports in the order they were given and dispatches to the first match.
+	Handles(req *http.Request) bool
+	// Handler returns the http.Handler that serves requests this transport handles, turning
+	// each connection into a Connection and handing it to server.Run.
+	Handler(server *Server) http.Handler
+}
+
+// multiplexTransports returns a single http.Handler that routes each request to the first
+// configured Transport willing to handle it, so all transports can share one mux path the way
+// SignalR clients expect after negotiation.
+func multiplexTransports(server *Server, transports []Transport) http.Handler {
+	handlers := make(map[string]http.Handler, len(transports))
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for _, transport := range transports {
+			if !transport.Handles(req) {
+				continue
+			}
+			h, ok := handlers[transport.Name()]
+			if !ok {
+				h = transport.Handler(server)
+				handlers[transport.Name()] = h
+			}
+			h.ServeHTTP(w, req)
+			return
+		}
+		http.Error(w, "no transport available for this request", http.StatusBadRequest)
+	})
+}
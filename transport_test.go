@@ -0,0 +1,76 @@
+package signalr
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewSSETransportIsExported(t *testing.T) {
+	transport := NewSSETransport()
+	if transport.Name() != "ServerSentEvents" {
+		t.Fatalf("expected ServerSentEvents, got %s", transport.Name())
+	}
+}
+
+func TestNewLongPollingTransportIsExported(t *testing.T) {
+	transport := NewLongPollingTransport()
+	if transport.Name() != "LongPolling" {
+		t.Fatalf("expected LongPolling, got %s", transport.Name())
+	}
+}
+
+func TestSSEConnectionReadBuffersOversizedFrame(t *testing.T) {
+	conn := newSSEConnection(context.Background(), "conn1")
+	conn.inbox <- []byte("0123456789")
+
+	p := make([]byte, 4)
+	var got []byte
+	for len(got) < 10 {
+		n, err := conn.Read(p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, p[:n]...)
+	}
+	if string(got) != "0123456789" {
+		t.Fatalf("expected frame delivered intact across reads, got %q", got)
+	}
+}
+
+// TestLongPollingTransportReleaseConnectionCleansUp confirms releaseConnection (called once
+// server.Run returns for a connection) both removes the transport's map entry and cancels the
+// connection's context, so no connectionID outlives the hub connection it served.
+func TestLongPollingTransportReleaseConnectionCleansUp(t *testing.T) {
+	transport := NewLongPollingTransport().(*longPollingTransport)
+	conn := newLongPollingConnection("conn1")
+	transport.connections["conn1"] = conn
+
+	transport.releaseConnection("conn1")
+
+	if _, ok := transport.connections["conn1"]; ok {
+		t.Fatalf("expected releaseConnection to remove the map entry")
+	}
+	select {
+	case <-conn.Context().Done():
+	default:
+		t.Fatalf("expected releaseConnection to cancel the connection's context")
+	}
+}
+
+func TestLongPollingConnectionReadBuffersOversizedFrame(t *testing.T) {
+	conn := newLongPollingConnection("conn1")
+	conn.inbox <- []byte("0123456789")
+
+	p := make([]byte, 4)
+	var got []byte
+	for len(got) < 10 {
+		n, err := conn.Read(p)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, p[:n]...)
+	}
+	if string(got) != "0123456789" {
+		t.Fatalf("expected frame delivered intact across reads, got %q", got)
+	}
+}
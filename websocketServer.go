@@ -9,22 +9,20 @@ import (
 	"net/http"
 )
 
-// MapHub used to register a SignalR Hub with the specified ServeMux
-func MapHub(mux *http.ServeMux, path string, hubProto HubInterface) *Server {
-	mux.HandleFunc(fmt.Sprintf("%s/negotiate", path), negotiateHandler)
-	server, _ := NewServer(SimpleTransientHubFactory(hubProto))
-	mux.Handle(path, websocket.Handler(func(ws *websocket.Conn) {
-		connectionID := ws.Request().URL.Query().Get("id")
-		if len(connectionID) == 0 {
-			// Support websocket connection without negotiate
-			connectionID = getConnectionID()
-		}
-		server.Run(&webSocketConnection{ws, nil, connectionID})
-	}))
+// MapHub registers a SignalR Hub with the specified ServeMux. transports controls which
+// transport(s) clients may negotiate, in priority order; when none are given it defaults to
+// WebSockets only, matching the previous behavior of this function.
+func MapHub(mux *http.ServeMux, path string, hubProto HubInterface, transports ...Transport) *Server {
+	if len(transports) == 0 {
+		transports = []Transport{&webSocketTransport{}}
+	}
+	server, _ := NewServer(SimpleTransientHubFactory(hubProto), WithTransports(transports...))
+	mux.HandleFunc(fmt.Sprintf("%s/negotiate", path), server.negotiateHandler)
+	mux.Handle(path, multiplexTransports(server, transports))
 	return server
 }
 
-func negotiateHandler(w http.ResponseWriter, req *http.Request) {
+func (s *Server) negotiateHandler(w http.ResponseWriter, req *http.Request) {
 	if req.Method != "POST" {
 		w.WriteHeader(400)
 		return
@@ -33,13 +31,16 @@ func negotiateHandler(w http.ResponseWriter, req *http.Request) {
 	connectionID := getConnectionID()
 
 	response := negotiateResponse{
-		ConnectionID: connectionID,
-		AvailableTransports: []availableTransport{
-			{
-				Transport:       "WebSockets",
-				TransferFormats: []string{"Text", "Binary"},
-			},
-		},
+		ConnectionID:          connectionID,
+		AvailableTransports:   make([]availableTransport, 0, len(s.transports)),
+		KeepAliveInterval:     int(s.keepAliveInterval.Milliseconds()),
+		ClientTimeoutInterval: int(s.clientTimeoutInterval.Milliseconds()),
+	}
+	for _, transport := range s.transports {
+		response.AvailableTransports = append(response.AvailableTransports, availableTransport{
+			Transport:       transport.Name(),
+			TransferFormats: transport.TransferFormats(),
+		})
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -61,6 +62,31 @@ type availableTransport struct {
 }
 
 type negotiateResponse struct {
-	ConnectionID        string               `json:"connectionId"`
-	AvailableTransports []availableTransport `json:"availableTransports"`
+	ConnectionID          string               `json:"connectionId"`
+	AvailableTransports   []availableTransport `json:"availableTransports"`
+	KeepAliveInterval     int                  `json:"keepAliveInterval"`
+	ClientTimeoutInterval int                  `json:"clientTimeoutInterval"`
+}
+
+// webSocketTransport is the default Transport, unchanged from the original WebSockets-only
+// implementation of MapHub.
+type webSocketTransport struct{}
+
+func (t *webSocketTransport) Name() string { return "WebSockets" }
+
+func (t *webSocketTransport) TransferFormats() []string { return []string{"Text", "Binary"} }
+
+func (t *webSocketTransport) Handles(req *http.Request) bool {
+	return req.Header.Get("Upgrade") == "websocket"
+}
+
+func (t *webSocketTransport) Handler(server *Server) http.Handler {
+	return websocket.Handler(func(ws *websocket.Conn) {
+		connectionID := ws.Request().URL.Query().Get("id")
+		if len(connectionID) == 0 {
+			// Support websocket connection without negotiate
+			connectionID = getConnectionID()
+		}
+		server.Run(&webSocketConnection{ws, nil, connectionID})
+	})
 }